@@ -0,0 +1,156 @@
+package main
+
+import "strings"
+
+// CanonType selects how aggressively language tags are normalized before
+// LanguageParser performs matching, mirroring the canonicalization levels
+// golang.org/x/text/language offers.
+type CanonType int
+
+const (
+	// Default case-folds subtags to their canonical case and resolves
+	// legacy deprecated tags to their IANA-registered replacement
+	// (e.g. "sh" to "sr-Latn").
+	Default CanonType = iota
+	// BCP47 applies RFC 5646 canonical form: a language-extlang pair is
+	// replaced by the extlang alone (e.g. "zh-cmn" to "cmn"), and a script
+	// subtag matching the language's suppressed script is dropped.
+	// Deprecated tags are not resolved under this policy.
+	BCP47
+	// Macro collapses macrolanguage extlangs and aliases into their
+	// macrolanguage (e.g. "zh-cmn" to "zh").
+	Macro
+	// All applies Default, BCP47, and Macro canonicalization together.
+	All
+)
+
+// deprecatedLanguages maps legacy IANA language subtags to their simple
+// replacement, for deprecated codes that don't also require a script or
+// region change.
+var deprecatedLanguages = map[string]string{
+	"iw": "he",
+	"in": "id",
+	"ji": "yi",
+	"mo": "ro",
+}
+
+// extlangMacroParents maps a small set of extlang subtags to the
+// macrolanguage they are spoken forms of, e.g. "cmn" (Mandarin) is an
+// extlang of the "zh" macrolanguage.
+var extlangMacroParents = map[string]string{
+	"cmn": "zh",
+	"yue": "zh",
+	"wuu": "zh",
+}
+
+// suppressScript lists languages whose script is implied and therefore
+// dropped under BCP47/All canonicalization when it is spelled out
+// explicitly in the tag.
+var suppressScript = map[string]string{
+	"en": "Latn",
+	"fr": "Latn",
+	"de": "Latn",
+	"he": "Latn",
+}
+
+// canonicalizeTag rewrites a single BCP 47 tag under the given policy. Only
+// the language, optional extlang, script, and region subtags are inspected;
+// any remaining subtags (variants, extensions, private use) are case-folded
+// to lowercase and passed through unchanged.
+func canonicalizeTag(tag string, ct CanonType) string {
+	parts := strings.Split(tag, "-")
+	if len(parts) == 0 || parts[0] == "" {
+		return tag
+	}
+
+	lang := strings.ToLower(parts[0])
+	rest := parts[1:]
+
+	var extlang string
+	if len(rest) > 0 && len(rest[0]) == 3 && isASCIIAlpha(rest[0]) {
+		if parent, ok := extlangMacroParents[strings.ToLower(rest[0])]; ok && parent == lang {
+			extlang = strings.ToLower(rest[0])
+		}
+	}
+	// Only BCP47 and Macro (directly, or via All) ever act on an extlang
+	// subtag; every other policy leaves it in place as an ordinary
+	// trailing subtag.
+	if extlang != "" && (ct == BCP47 || ct == Macro || ct == All) {
+		rest = rest[1:]
+	} else {
+		extlang = ""
+	}
+
+	var script, region string
+	var others []string
+	for i, part := range rest {
+		switch {
+		case i == 0 && script == "" && len(part) == 4 && isASCIIAlpha(part):
+			script = titleCase(part)
+		case (len(part) == 2 && isASCIIAlpha(part)) || (len(part) == 3 && isASCIIDigit(part)):
+			region = strings.ToUpper(part)
+		default:
+			others = append(others, strings.ToLower(part))
+		}
+	}
+
+	applyDeprecated := func() {
+		if alias, ok := macroLanguageAliases[lang]; ok {
+			lang = alias.lang
+			if script == "" {
+				script = alias.script
+			}
+		}
+		if repl, ok := deprecatedLanguages[lang]; ok {
+			lang = repl
+		}
+	}
+	extlangConsumed := false
+	applyMacro := func() {
+		if extlang != "" {
+			// lang already holds the macrolanguage prefix; dropping the
+			// extlang subtag (already removed from rest above) collapses
+			// the tag into its macrolanguage. Mark it consumed so a later
+			// applyBCP47 (under All) doesn't re-promote it.
+			extlangConsumed = true
+			return
+		}
+		if alias, ok := macroLanguageAliases[lang]; ok {
+			lang = alias.lang
+			if script == "" {
+				script = alias.script
+			}
+		}
+	}
+	applyBCP47 := func() {
+		if extlang != "" && !extlangConsumed {
+			lang = extlang
+		}
+		if suppressScript[lang] == script {
+			script = ""
+		}
+	}
+
+	switch ct {
+	case Default:
+		applyDeprecated()
+	case BCP47:
+		applyBCP47()
+	case Macro:
+		applyMacro()
+	case All:
+		applyDeprecated()
+		applyMacro()
+		applyBCP47()
+	}
+
+	out := []string{lang}
+	if script != "" {
+		out = append(out, script)
+	}
+	if region != "" {
+		out = append(out, region)
+	}
+	out = append(out, others...)
+	return strings.Join(out, "-")
+}
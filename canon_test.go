@@ -0,0 +1,83 @@
+package main
+
+import "testing"
+
+// TestCanonicalizeTag mirrors x/text's ExampleCanonType: the same input tag
+// canonicalizes differently depending on policy.
+func TestCanonicalizeTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		ct   CanonType
+		want string
+	}{
+		{"Default resolves a deprecated tag to its replacement", "sh", Default, "sr-Latn"},
+		{"BCP47 drops a redundant script subtag", "en-Latn", BCP47, "en"},
+		{"Macro collapses an extlang into its macrolanguage", "zh-cmn", Macro, "zh"},
+		{"All combines deprecated, macro, and script-suppression rules", "iw-Latn-fonipa", All, "he-fonipa"},
+		{"Default leaves an extlang alone aside from case-folding", "zh-cmn", Default, "zh-cmn"},
+		{"BCP47 promotes an extlang to replace its macrolanguage prefix", "zh-cmn", BCP47, "cmn"},
+		{"All agrees with Macro alone: the extlang collapses into zh, not cmn", "zh-cmn", All, "zh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := canonicalizeTag(tt.tag, tt.ct)
+			if got != tt.want {
+				t.Errorf("canonicalizeTag(%q, %v) = %q, want %q", tt.tag, tt.ct, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLanguageParserWithCanonicalization checks that WithCanonicalization
+// matches on canonical forms while returning the caller's original spelling.
+func TestLanguageParserWithCanonicalization(t *testing.T) {
+	tests := []struct {
+		name      string
+		ct        CanonType
+		header    string
+		supported []string
+		expected  []string
+	}{
+		{
+			name:      "Default fixes case-sensitivity",
+			ct:        Default,
+			header:    "en-us, fr-ca",
+			supported: []string{"en-US", "fr-CA"},
+			expected:  []string{"en-US", "fr-CA"},
+		},
+		{
+			name:      "Default resolves a deprecated tag in the header",
+			ct:        Default,
+			header:    "iw",
+			supported: []string{"he", "en-US"},
+			expected:  []string{"he"},
+		},
+		{
+			name:      "BCP47 matches a redundant script in the header",
+			ct:        BCP47,
+			header:    "en-Latn",
+			supported: []string{"en"},
+			expected:  []string{"en"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewLanguageParser().WithCanonicalization(tt.ct)
+			result, err := parser.Parse(tt.header, tt.supported)
+			if err != nil {
+				t.Fatalf("Parse(%q, %v) unexpected error: %v", tt.header, tt.supported, err)
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Parse(%q, %v) = %v, want %v", tt.header, tt.supported, result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("Parse(%q, %v) = %v, want %v", tt.header, tt.supported, result, tt.expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ParseRequest reads the Accept-Language header(s) from r and returns the
+// supported languages in the client's preference order, using the same
+// q-value-aware matching as Parse. Multiple Accept-Language header lines
+// are joined with commas per RFC 7230 section 3.2.2 before parsing. Any
+// malformed q-value error from Parse is discarded; callers that need it
+// should call Parse directly with the joined header.
+func (lp *LanguageParser) ParseRequest(r *http.Request, supported []string) []string {
+	header := strings.Join(r.Header.Values("Accept-Language"), ", ")
+	result, _ := lp.Parse(header, supported)
+	return result
+}
+
+// contextKey is an unexported type so LanguageContextKey can't collide with
+// context keys defined by other packages.
+type contextKey string
+
+// LanguageContextKey is the context key Middleware stores the negotiated
+// language under. Retrieve it with LanguageFromContext.
+const LanguageContextKey contextKey = "language-parser.language"
+
+// Options configures Middleware's language negotiation.
+type Options struct {
+	// CookieName, if set, lets a request override negotiation by sending
+	// a cookie of this name whose value is one of the supported tags.
+	// Defaults to "lang".
+	CookieName string
+	// QueryParam, if set, lets a request override negotiation with a URL
+	// query parameter of this name, checked after the cookie and before
+	// the Accept-Language header. Defaults to "lang".
+	QueryParam string
+	// FallbackLanguage is returned when no cookie, query parameter, or
+	// Accept-Language header produces a supported match.
+	FallbackLanguage string
+}
+
+// Middleware returns http middleware that negotiates a language for each
+// request from supported, storing the result in the request context under
+// LanguageContextKey and setting it as the response's Content-Language
+// header. It is equivalent to MiddlewareWithOptions with the cookie and
+// query parameter names defaulted to "lang".
+func Middleware(supported []string, fallback string) func(http.Handler) http.Handler {
+	return MiddlewareWithOptions(supported, Options{FallbackLanguage: fallback})
+}
+
+// MiddlewareWithOptions is like Middleware but lets the cookie name, query
+// parameter name, and fallback language be configured. A zero-value
+// CookieName or QueryParam falls back to "lang"; set one to "-" to disable
+// that override source entirely.
+func MiddlewareWithOptions(supported []string, opts Options) func(http.Handler) http.Handler {
+	if opts.CookieName == "" {
+		opts.CookieName = "lang"
+	}
+	if opts.QueryParam == "" {
+		opts.QueryParam = "lang"
+	}
+
+	supportedSet := make(map[string]bool, len(supported))
+	for _, s := range supported {
+		supportedSet[s] = true
+	}
+
+	parser := NewLanguageParser()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lang := opts.FallbackLanguage
+
+			switch {
+			case opts.CookieName != "-" && supportedSet[cookieValue(r, opts.CookieName)]:
+				lang = cookieValue(r, opts.CookieName)
+			case opts.QueryParam != "-" && supportedSet[r.URL.Query().Get(opts.QueryParam)]:
+				lang = r.URL.Query().Get(opts.QueryParam)
+			default:
+				if matches := parser.ParseRequest(r, supported); len(matches) > 0 {
+					lang = matches[0]
+				}
+			}
+
+			w.Header().Set("Content-Language", lang)
+			ctx := context.WithValue(r.Context(), LanguageContextKey, lang)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// cookieValue returns the named cookie's value, or "" if it isn't present.
+func cookieValue(r *http.Request, name string) string {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// LanguageFromContext returns the language Middleware negotiated for the
+// request, or "" if ctx wasn't derived from a request Middleware handled.
+func LanguageFromContext(ctx context.Context) string {
+	lang, _ := ctx.Value(LanguageContextKey).(string)
+	return lang
+}
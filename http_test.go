@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Add("Accept-Language", "fr-CA;q=0.8")
+	req.Header.Add("Accept-Language", "en-US")
+
+	parser := NewLanguageParser()
+	result := parser.ParseRequest(req, []string{"en-US", "fr-CA"})
+
+	want := []string{"en-US", "fr-CA"}
+	if len(result) != len(want) {
+		t.Fatalf("ParseRequest() = %v, want %v", result, want)
+	}
+	for i := range result {
+		if result[i] != want[i] {
+			t.Errorf("ParseRequest() = %v, want %v", result, want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	supported := []string{"en-US", "fr-FR"}
+
+	handler := Middleware(supported, "en-US")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(LanguageFromContext(r.Context())))
+	}))
+
+	tests := []struct {
+		name       string
+		setup      func(r *http.Request)
+		wantBody   string
+		wantHeader string
+	}{
+		{
+			name:       "Falls back when nothing matches",
+			setup:      func(r *http.Request) {},
+			wantBody:   "en-US",
+			wantHeader: "en-US",
+		},
+		{
+			name: "Negotiates from the Accept-Language header",
+			setup: func(r *http.Request) {
+				r.Header.Set("Accept-Language", "fr-FR")
+			},
+			wantBody:   "fr-FR",
+			wantHeader: "fr-FR",
+		},
+		{
+			name: "Cookie overrides the header",
+			setup: func(r *http.Request) {
+				r.Header.Set("Accept-Language", "en-US")
+				r.AddCookie(&http.Cookie{Name: "lang", Value: "fr-FR"})
+			},
+			wantBody:   "fr-FR",
+			wantHeader: "fr-FR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			tt.setup(req)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if got := rec.Header().Get("Content-Language"); got != tt.wantHeader {
+				t.Errorf("Content-Language = %q, want %q", got, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestLanguageFromContextWithoutMiddleware(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if got := LanguageFromContext(req.Context()); got != "" {
+		t.Errorf("LanguageFromContext() = %q, want empty string", got)
+	}
+}
@@ -20,6 +20,10 @@ import (
 //   - Exact matches take priority over generic matches
 //   - When a generic tag matches multiple variants, all are returned in supported order
 //
+// Part 3: Wildcard support
+//   - "*" matches every supported language that has not already been matched
+//   - Matches are appended in supported order
+//
 // Parameters:
 //   - header: The Accept-Language header value as a string (e.g., "en-US, fr-CA, fr-FR")
 //   - supported: A slice of language tags that the server supports
@@ -33,17 +37,36 @@ func parseAcceptLanguage(header string, supported []string) []string {
 		return []string{}
 	}
 
+	// Parse the header by splitting on commas and trimming whitespace
+	clientPrefs := strings.Split(header, ",")
+	prefs := make([]string, 0, len(clientPrefs))
+	for _, pref := range clientPrefs {
+		lang := strings.TrimSpace(pref)
+		if lang == "" {
+			continue // Skip empty entries from trailing commas or extra spaces
+		}
+		prefs = append(prefs, lang)
+	}
+
+	return matchPreferences(prefs, supported)
+}
+
+// matchPreferences applies exact, generic, and wildcard matching to an
+// already-split, already-trimmed, preference-ordered list of language tags.
+// It is shared by parseAcceptLanguage and LanguageParser.Parse so that the
+// two entry points (plain-tag and q-value aware) agree on match semantics.
+func matchPreferences(clientPrefs []string, supported []string) []string {
 	// Build lookup structures for supported languages
 	// Part 1: Exact match lookup
 	supportedExact := make(map[string]bool)
-	
+
 	// Part 2: Generic match lookup - maps generic tags to their specific variants
 	supportedGeneric := make(map[string][]string)
-	
+
 	for _, lang := range supported {
 		// Store for exact matching (Part 1)
 		supportedExact[lang] = true
-		
+
 		// Extract generic part for generic matching (Part 2)
 		// Everything before the first hyphen becomes the generic tag
 		parts := strings.SplitN(lang, "-", 2)
@@ -51,16 +74,20 @@ func parseAcceptLanguage(header string, supported []string) []string {
 		supportedGeneric[generic] = append(supportedGeneric[generic], lang)
 	}
 
-	// Parse the header by splitting on commas and trimming whitespace
-	clientPrefs := strings.Split(header, ",")
 	result := make([]string, 0)
 	seen := make(map[string]bool) // Track added languages to prevent duplicates
 
 	// Process each client preference in order (maintaining preference order)
-	for _, pref := range clientPrefs {
-		lang := strings.TrimSpace(pref)
-		if lang == "" {
-			continue // Skip empty entries from trailing commas or extra spaces
+	for _, lang := range clientPrefs {
+		// PART 3: "*" matches everything not yet matched, in supported order
+		if lang == "*" {
+			for _, variant := range supported {
+				if !seen[variant] {
+					result = append(result, variant)
+					seen[variant] = true
+				}
+			}
+			continue
 		}
 
 		// PART 1: Check for exact match first (higher priority)
@@ -88,24 +115,6 @@ func parseAcceptLanguage(header string, supported []string) []string {
 	return result
 }
 
-// LanguageParser provides an enhanced struct-based implementation
-type LanguageParser struct {
-	// 
-	
-}
-
-// NewLanguageParser creates a new instance of LanguageParser
-func NewLanguageParser() *LanguageParser {
-	return &LanguageParser{}
-}
-
-// Parse implements the same logic as parseAcceptLanguage but in a struct-based approach
-// for better extensibility and testability
-func (lp *LanguageParser) Parse(header string, supported []string) []string {
-	// Reuse the existing function for consistency
-	return parseAcceptLanguage(header, supported)
-}
-
 // main demonstrates all examples from both Part 1 and Part 2
 func main() {
 	fmt.Println("ACCEPT-LANGUAGE PARSER")
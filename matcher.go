@@ -0,0 +1,287 @@
+package main
+
+import "strings"
+
+// Confidence reports how closely a Matcher's chosen tag satisfies the
+// desired language, modeled on golang.org/x/text/language's scale.
+type Confidence int
+
+const (
+	// No means nothing usable was found; the returned tag is an arbitrary
+	// fallback and should not be presented as a real match.
+	No Confidence = iota
+	// Low means a plausible but uncertain match (e.g. a bare wildcard, or
+	// a same-language match whose scripts conflict but whose regions agree).
+	Low
+	// High means the same language (directly or via a macro-language
+	// equivalence), with a compatible or unspecified script.
+	High
+	// Exact means the tag matches bit-for-bit after subtag normalization.
+	Exact
+)
+
+// String renders the confidence the way x/text does, for log/debug output.
+func (c Confidence) String() string {
+	switch c {
+	case Exact:
+		return "Exact"
+	case High:
+		return "High"
+	case Low:
+		return "Low"
+	default:
+		return "No"
+	}
+}
+
+// subtags is the minimal BCP 47 decomposition this package cares about:
+// primary language, script, and region. Each field is normalized to its
+// canonical case (language lower, script Title, region upper) so that two
+// equivalent spellings of a tag compare equal.
+type subtags struct {
+	lang, script, region string
+}
+
+// macroLanguageAliases maps a handful of deprecated or macro-language tags
+// to the representative subtags CLDR resolves them to. This is intentionally
+// a small, hand-picked table rather than the full CLDR supplemental data.
+var macroLanguageAliases = map[string]subtags{
+	"cmn": {lang: "zh"},
+	"sh":  {lang: "sr", script: "Latn"},
+}
+
+// impliedScripts gives the script a region implies for a language when the
+// tag itself doesn't specify one, e.g. "zh-TW" is conventionally written in
+// the Hant script. Only the languages this package has tests for are listed.
+var impliedScripts = map[string]map[string]string{
+	"zh": {"TW": "Hant", "HK": "Hant", "MO": "Hant", "CN": "Hans", "SG": "Hans"},
+}
+
+// containedGroupings is a minimal slice of CLDR's region-containment data
+// ("grouping" regions like UN M49 area code 419 for Latin America). It lets
+// the matcher prefer a regional variant like "es-419" over a bare "es" when
+// the desired region falls inside that grouping.
+var containedGroupings = map[string][]string{
+	"419": {"MX", "AR", "CL", "CO", "PE", "VE", "BR", "EC", "BO", "PY", "UY", "CR", "GT", "HN", "NI", "PA", "SV", "DO", "PR", "CU"},
+	"150": {"DE", "FR", "IT", "ES", "GB", "NL", "BE", "CH", "AT", "PL", "SE", "NO", "DK", "FI", "PT", "IE", "GR"},
+}
+
+func isASCIIAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+func isASCIIDigit(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + strings.ToLower(s[1:])
+}
+
+// parseSubtags splits a BCP 47 tag into its language, script, and region,
+// case-folding each to its canonical form for comparison. Anything beyond
+// these three subtags (variants, extensions, private use) is ignored.
+func parseSubtags(tag string) subtags {
+	var st subtags
+	parts := strings.Split(tag, "-")
+	if len(parts) == 0 {
+		return st
+	}
+	st.lang = strings.ToLower(parts[0])
+	for _, part := range parts[1:] {
+		switch {
+		case len(part) == 4 && isASCIIAlpha(part):
+			st.script = titleCase(part)
+		case (len(part) == 2 && isASCIIAlpha(part)) || (len(part) == 3 && isASCIIDigit(part)):
+			st.region = strings.ToUpper(part)
+		}
+	}
+	return st
+}
+
+// resolveMacro substitutes a macro-language or deprecated tag (e.g. "cmn",
+// "sh") with the subtags CLDR treats it as equivalent to, keeping any script
+// or region the original tag specified explicitly.
+func resolveMacro(st subtags) subtags {
+	alias, ok := macroLanguageAliases[st.lang]
+	if !ok {
+		return st
+	}
+	resolved := alias
+	if st.script != "" {
+		resolved.script = st.script
+	}
+	if st.region != "" {
+		resolved.region = st.region
+	}
+	return resolved
+}
+
+func impliedScript(lang, region string) string {
+	if region == "" {
+		return ""
+	}
+	return impliedScripts[lang][region]
+}
+
+func regionEncloses(group, region string) bool {
+	for _, r := range containedGroupings[group] {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// scoreCandidate compares a desired tag against one supported tag and
+// returns its confidence plus an internal specificity rank used only to
+// break ties between candidates that share the same confidence (e.g. an
+// enclosing-region match should beat a scriptless bare-language match even
+// though both are High).
+func scoreCandidate(d, s subtags) (Confidence, int) {
+	if d == s && d.lang != "" {
+		return Exact, 100
+	}
+
+	// Macro-language equivalence is bidirectional (cmn<->zh, sh<->sr-Latn):
+	// resolve both sides so a supported "cmn" satisfies a desired "zh" just
+	// as readily as a desired "cmn" satisfies a supported "zh".
+	rd := resolveMacro(d)
+	rs := resolveMacro(s)
+	if rd.lang != rs.lang {
+		return No, 0
+	}
+
+	effDScript := rd.script
+	if effDScript == "" {
+		effDScript = impliedScript(rd.lang, rd.region)
+	}
+	effSScript := rs.script
+	if effSScript == "" {
+		effSScript = impliedScript(rs.lang, rs.region)
+	}
+	scriptsKnown := effDScript != "" && effSScript != ""
+
+	if scriptsKnown && effDScript != effSScript {
+		// Genuine script conflict (e.g. sr-Latn vs sr-Cyrl): only a shared
+		// region can bridge it, otherwise the scripts are incompatible.
+		if d.region != "" && d.region == s.region {
+			return Low, 10
+		}
+		return No, 0
+	}
+
+	switch {
+	case d.region != "" && s.region != "" && d.region == s.region:
+		return High, 95
+	case d.region != "" && s.region != "" && regionEncloses(s.region, d.region):
+		return High, 90
+	case d.region != "" && s.region != "" && regionEncloses(d.region, s.region):
+		return High, 85
+	case d.region != "" && s.region != "":
+		return High, 80
+	case scriptsKnown:
+		return High, 70
+	default:
+		return High, 60
+	}
+}
+
+// Matcher picks the best of a fixed set of supported language tags for a
+// client's desired tags, modeled on CLDR's locale matching algorithm.
+// Unlike LanguageParser, which returns every acceptable supported tag in
+// preference order, Matcher returns a single best pick plus a Confidence
+// so callers can decide whether to trust it.
+type Matcher struct {
+	supported []string
+	parsed    []subtags
+}
+
+// NewMatcher builds a Matcher over a fixed list of supported language tags.
+func NewMatcher(supported []string) *Matcher {
+	parsed := make([]subtags, len(supported))
+	for i, s := range supported {
+		parsed[i] = parseSubtags(s)
+	}
+	return &Matcher{supported: supported, parsed: parsed}
+}
+
+// candidateMatch pairs a scored supported tag with the position of the
+// desired tag that produced it, so Match can prefer earlier desired
+// preferences when two candidates tie on confidence and rank.
+type candidateMatch struct {
+	supportedIdx int
+	desiredIdx   int
+	conf         Confidence
+	rank         int
+}
+
+func (c candidateMatch) betterThan(best *candidateMatch) bool {
+	if best == nil {
+		return true
+	}
+	if c.conf != best.conf {
+		return c.conf > best.conf
+	}
+	if c.rank != best.rank {
+		return c.rank > best.rank
+	}
+	if c.desiredIdx != best.desiredIdx {
+		return c.desiredIdx < best.desiredIdx
+	}
+	return c.supportedIdx < best.supportedIdx
+}
+
+// Match returns the best supported tag for the given desired tags (in
+// preference order), the matched tag's index in the supported list, and a
+// Confidence describing how good the match is. A literal "*" in desired
+// picks the first supported tag with Low confidence, but only if nothing
+// else scored higher. If supported is empty, Match returns ("", -1, No).
+// If nothing matches at all, it falls back to the first supported tag with
+// No confidence, the same "always return something" contract x/text uses.
+func (m *Matcher) Match(desired ...string) (tag string, index int, conf Confidence) {
+	if len(m.supported) == 0 {
+		return "", -1, No
+	}
+
+	var best *candidateMatch
+	for di, want := range desired {
+		if want == "*" {
+			c := candidateMatch{supportedIdx: 0, desiredIdx: di, conf: Low, rank: -1000}
+			if c.betterThan(best) {
+				best = &c
+			}
+			continue
+		}
+
+		d := parseSubtags(want)
+		for si, s := range m.parsed {
+			conf, rank := scoreCandidate(d, s)
+			if conf == No {
+				continue
+			}
+			c := candidateMatch{supportedIdx: si, desiredIdx: di, conf: conf, rank: rank}
+			if c.betterThan(best) {
+				best = &c
+			}
+		}
+	}
+
+	if best == nil {
+		return m.supported[0], 0, No
+	}
+	return m.supported[best.supportedIdx], best.supportedIdx, best.conf
+}
@@ -0,0 +1,106 @@
+package main
+
+import "testing"
+
+func TestMatcherMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		supported []string
+		desired   []string
+		wantTag   string
+		wantIndex int
+		wantConf  Confidence
+	}{
+		{
+			name:      "Exact tag match",
+			supported: []string{"en-US", "fr-FR"},
+			desired:   []string{"en-US"},
+			wantTag:   "en-US",
+			wantIndex: 0,
+			wantConf:  Exact,
+		},
+		{
+			name:      "Region grouping preferred over bare language",
+			supported: []string{"es-419", "es"},
+			desired:   []string{"es-MX"},
+			wantTag:   "es-419",
+			wantIndex: 0,
+			wantConf:  High,
+		},
+		{
+			name:      "Region implies script for zh",
+			supported: []string{"zh-Hant", "zh-Hans"},
+			desired:   []string{"zh-TW"},
+			wantTag:   "zh-Hant",
+			wantIndex: 0,
+			wantConf:  High,
+		},
+		{
+			name:      "Macro-language equivalence cmn to zh",
+			supported: []string{"zh", "en"},
+			desired:   []string{"cmn"},
+			wantTag:   "zh",
+			wantIndex: 0,
+			wantConf:  High,
+		},
+		{
+			name:      "Macro-language equivalence sh to sr-Latn",
+			supported: []string{"sr-Latn", "sr-Cyrl"},
+			desired:   []string{"sh"},
+			wantTag:   "sr-Latn",
+			wantIndex: 0,
+			wantConf:  High,
+		},
+		{
+			name:      "Macro-language equivalence is bidirectional: zh desired matches a supported cmn",
+			supported: []string{"cmn", "en"},
+			desired:   []string{"zh"},
+			wantTag:   "cmn",
+			wantIndex: 0,
+			wantConf:  High,
+		},
+		{
+			name:      "Script conflict within same language without a bridging region",
+			supported: []string{"sr-Cyrl"},
+			desired:   []string{"sr-Latn"},
+			wantTag:   "sr-Cyrl",
+			wantIndex: 0,
+			wantConf:  No,
+		},
+		{
+			name:      "No real match falls back to the first supported tag",
+			supported: []string{"en", "fr"},
+			desired:   []string{"sh"},
+			wantTag:   "en",
+			wantIndex: 0,
+			wantConf:  No,
+		},
+		{
+			name:      "Wildcard only wins when nothing else matched",
+			supported: []string{"en", "fr"},
+			desired:   []string{"de", "*"},
+			wantTag:   "en",
+			wantIndex: 0,
+			wantConf:  Low,
+		},
+		{
+			name:      "Wildcard loses to a real match appearing later in desired",
+			supported: []string{"en", "fr"},
+			desired:   []string{"*", "fr"},
+			wantTag:   "fr",
+			wantIndex: 1,
+			wantConf:  Exact,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatcher(tt.supported)
+			tag, index, conf := m.Match(tt.desired...)
+			if tag != tt.wantTag || index != tt.wantIndex || conf != tt.wantConf {
+				t.Errorf("Match(%v) over %v = (%q, %d, %s), want (%q, %d, %s)",
+					tt.desired, tt.supported, tag, index, conf, tt.wantTag, tt.wantIndex, tt.wantConf)
+			}
+		})
+	}
+}
@@ -1,8 +1,13 @@
 // package main provides enhanced parser functionality for Accept-Language headers
 
-package main 
+package main
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -12,51 +17,143 @@ import (
 // configuring options (case-insensitive matching, etc)
 //statistics collection
 type LanguageParser struct {
-	// 
+	// canon is nil until WithCanonicalization is called, so Parse's
+	// default behavior (no canonicalization) is unchanged for existing
+	// callers.
+	canon *CanonType
 }
 
 // NewLanguageParser creates a new instance of LanguageParser
 func NewLanguageParser() *LanguageParser {
-	return  &LanguageParser{}
+	return &LanguageParser{}
 }
 
-// parse accepts an Accept-Language header and returns supported languages
-// in the client's preference order.
-// this is the enhanced version of paseAcceptLanguage with better error handling and separation of concerns
-// it implements the same logic but in a more maintainable structure
-func (lp *LanguageParser) Parse(header string, supported []string) []string {
-	// early returns for edge cases
-	if header == "" {
-		return  []string{}
+// WithCanonicalization enables tag canonicalization for subsequent Parse
+// calls at the given policy level and returns lp for chaining. Both the
+// header's entries and the supported list are normalized to their
+// canonical form before matching; the strings Parse returns are always the
+// caller's original supported-list spellings.
+func (lp *LanguageParser) WithCanonicalization(ct CanonType) *LanguageParser {
+	lp.canon = &ct
+	return lp
+}
+
+// qValuePattern matches a valid RFC 7231 qvalue: "0", "0.x", "0.xx", "0.xxx",
+// "1", "1.0", "1.00", or "1.000".
+var qValuePattern = regexp.MustCompile(`^(?:0(?:\.[0-9]{1,3})?|1(?:\.0{1,3})?)$`)
+
+// preference is a single Accept-Language entry together with the quality
+// value it was given (defaulting to 1.0 when absent).
+type preference struct {
+	tag string
+	q   float64
+}
+
+// parseQValue parses the value of a "q" parameter per RFC 7231 section 5.3.1:
+// a number between 0 and 1 with at most three decimal digits.
+func parseQValue(raw string) (float64, error) {
+	if !qValuePattern.MatchString(raw) {
+		return 0, fmt.Errorf("invalid q-value %q", raw)
+	}
+	return strconv.ParseFloat(raw, 64)
+}
+
+// splitPreferences splits an Accept-Language header into preferences sorted
+// by descending q-value (original header order breaks ties), dropping any
+// entry with q=0 since RFC 7231 treats that as an explicit refusal. A
+// malformed q-value does not drop its entry: it falls back to the default
+// q=1.0, and the problem is collected into the returned error instead of
+// being silently ignored.
+func splitPreferences(header string) ([]preference, error) {
+	var prefs []preference
+	var errs []error
+
+	for _, entry := range strings.Split(header, ",") {
+		params := strings.Split(entry, ";")
+		tag := strings.TrimSpace(params[0])
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, found := strings.Cut(param, "=")
+			if !found || !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+			parsed, err := parseQValue(strings.TrimSpace(value))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", tag, err))
+				continue
+			}
+			q = parsed
+		}
+
+		if q == 0 {
+			continue
+		}
+		prefs = append(prefs, preference{tag: tag, q: q})
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].q > prefs[j].q
+	})
+
+	if len(errs) > 0 {
+		return prefs, fmt.Errorf("parse Accept-Language: %w", errors.Join(errs...))
 	}
+	return prefs, nil
+}
 
-	if len(supported) == 0 {
-		return []string{}
+// Parse parses an Accept-Language header and returns supported languages in
+// preference order, honoring RFC 7231 quality values ("q" parameters) on top
+// of the exact/generic/wildcard matching implemented by parseAcceptLanguage.
+// Preferences are sorted by descending q before matching, and q=0 entries are
+// dropped as explicit refusals. Malformed q-values fall back to q=1.0 rather
+// than discarding the entry; callers that care can inspect the returned
+// error. parseAcceptLanguage remains available as a q-value-unaware
+// equivalent for callers that predate this method.
+func (lp *LanguageParser) Parse(header string, supported []string) ([]string, error) {
+	if header == "" || len(supported) == 0 {
+		return []string{}, nil
 	}
 
-	// build lookup set for supported languages
-	supportedSet := make(map[string]struct{})
-	for _, lang := range supported {
-		supportedSet[lang] = struct{}{}
+	prefs, err := splitPreferences(header)
+	tags := make([]string, len(prefs))
+	for i, p := range prefs {
+		tags[i] = p.tag
 	}
 
-	// parse header and filter
-	preferences := strings.Split(header, ",")
-	result := make([]string, 0)
-	seen := make(map[string]bool)
+	if lp.canon == nil {
+		return matchPreferences(tags, supported), err
+	}
 
-	for _, pref := range preferences {
-		lang := strings.TrimSpace(pref)
-		if lang == "" {
+	canonTags := make([]string, len(tags))
+	for i, t := range tags {
+		if t == "*" {
+			canonTags[i] = t
 			continue
 		}
+		canonTags[i] = canonicalizeTag(t, *lp.canon)
+	}
 
-		// check if supported and not already in result
-		if _, ok := supportedSet[lang]; ok && !seen[lang] {
-			result = append(result, lang)
-			seen[lang] = true
+	// Map each canonical supported form back to the spelling the caller
+	// declared, so matches are returned in the caller's original casing
+	// even though matching itself happens on canonical forms.
+	canonSupported := make([]string, len(supported))
+	original := make(map[string]string, len(supported))
+	for i, s := range supported {
+		c := canonicalizeTag(s, *lp.canon)
+		canonSupported[i] = c
+		if _, exists := original[c]; !exists {
+			original[c] = s
 		}
 	}
 
-	return result
-}
\ No newline at end of file
+	matched := matchPreferences(canonTags, canonSupported)
+	result := make([]string, len(matched))
+	for i, m := range matched {
+		result[i] = original[m]
+	}
+	return result, err
+}
@@ -211,40 +211,77 @@ Got: %v
 // TestLanguageParser tests the struct-based implementation
 func TestLanguageParser(t *testing.T) {
 	parser := NewLanguageParser()
-	
+
 	tests := []struct {
-		name     string
-		header   string
+		name      string
+		header    string
 		supported []string
-		expected []string
+		expected  []string
+		wantErr   bool
 	}{
 		{
-			name:     "Parser - Basic exact match",
-			header:   "en-US, fr-CA, fr-FR",
+			name:      "Parser - Basic exact match",
+			header:    "en-US, fr-CA, fr-FR",
 			supported: []string{"fr-FR", "en-US"},
-			expected: []string{"en-US", "fr-FR"},
+			expected:  []string{"en-US", "fr-FR"},
+		},
+		{
+			name:      "Parser - Generic match",
+			header:    "fr",
+			supported: []string{"en-US", "fr-CA", "fr-FR"},
+			expected:  []string{"fr-CA", "fr-FR"},
 		},
 		{
-			name:     "Parser - Generic match",
-			header:   "fr",
+			name:      "Parser - Wildcard match",
+			header:    "en-US, *",
 			supported: []string{"en-US", "fr-CA", "fr-FR"},
-			expected: []string{"fr-CA", "fr-FR"},
+			expected:  []string{"en-US", "fr-CA", "fr-FR"},
 		},
 		{
-			name:     "Parser - Wildcard match",
-			header:   "en-US, *",
+			name:      "Parser - Wildcard with q=0 is suppressed",
+			header:    "en-US;q=0.5, *;q=0",
 			supported: []string{"en-US", "fr-CA", "fr-FR"},
-			expected: []string{"en-US", "fr-CA", "fr-FR"},
+			expected:  []string{"en-US"},
+		},
+		{
+			name:      "Parser - Exact beats generic on higher q even when generic appears first",
+			header:    "en;q=0.8, en-US;q=0.9",
+			supported: []string{"en-US", "en-GB"},
+			expected:  []string{"en-US", "en-GB"},
+		},
+		{
+			name:      "Parser - Whitespace around q parameter",
+			header:    "en-US ; q=0.5 , fr-FR ; q=0.9",
+			supported: []string{"en-US", "fr-FR"},
+			expected:  []string{"fr-FR", "en-US"},
+		},
+		{
+			name:      "Parser - Malformed q-value falls back to 1.0 and reports an error",
+			header:    "fr-FR;q=bogus, en-US;q=0.5",
+			supported: []string{"en-US", "fr-FR"},
+			expected:  []string{"fr-FR", "en-US"},
+			wantErr:   true,
+		},
+		{
+			name:      "Parser - Out-of-range q-value falls back to 1.0 and reports an error",
+			header:    "fr-FR;q=1.5, en-US;q=0.5",
+			supported: []string{"en-US", "fr-FR"},
+			expected:  []string{"fr-FR", "en-US"},
+			wantErr:   true,
 		},
 	}
-	
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parser.Parse(tt.header, tt.supported)
+			result, err := parser.Parse(tt.header, tt.supported)
 			if !reflect.DeepEqual(result, tt.expected) {
-				t.Errorf("Parser.Parse(%q, %v) = %v, expected %v", 
+				t.Errorf("Parser.Parse(%q, %v) = %v, expected %v",
 					tt.header, tt.supported, result, tt.expected)
 			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parser.Parse(%q, %v) error = %v, wantErr %v",
+					tt.header, tt.supported, err, tt.wantErr)
+			}
 		})
 	}
 }
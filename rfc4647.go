@@ -0,0 +1,103 @@
+package main
+
+import "strings"
+
+// FilterMode selects which of RFC 4647's two matching schemes Filter uses.
+type FilterMode int
+
+const (
+	// Lookup returns at most one tag: the best match for the first
+	// language range in header that matches anything at all.
+	Lookup FilterMode = iota
+	// Filtering returns every supported tag matched by any language range
+	// in header, in preference-then-supported order.
+	Filtering
+)
+
+// splitRanges splits a comma-separated header into trimmed, non-empty
+// language ranges, preserving their preference order.
+func splitRanges(header string) []string {
+	raw := strings.Split(header, ",")
+	ranges := make([]string, 0, len(raw))
+	for _, r := range raw {
+		r = strings.TrimSpace(r)
+		if r != "" {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// effectiveRangeSubtags splits a language range into subtags, dropping the
+// first singleton (single-character) subtag and everything after it: per
+// RFC 4647 section 3.3.2, extension and private-use subtags introduced by a
+// singleton are ignored for basic filtering and lookup purposes.
+func effectiveRangeSubtags(rng string) []string {
+	subs := strings.Split(rng, "-")
+	for i := 1; i < len(subs); i++ {
+		if len(subs[i]) == 1 {
+			return subs[:i]
+		}
+	}
+	return subs
+}
+
+// rangeMatchesTag reports whether language range rng matches supported tag
+// s: s's subtag slice must start with rng's effective subtag slice,
+// compared case-insensitively.
+func rangeMatchesTag(rng, s string) bool {
+	r := effectiveRangeSubtags(rng)
+	t := strings.Split(s, "-")
+	if len(r) > len(t) {
+		return false
+	}
+	for i, rs := range r {
+		if !strings.EqualFold(rs, t[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter implements the two RFC 4647 matching schemes over a comma-separated
+// Accept-Language-style header. Filtering returns every supported tag any
+// range in header extends (e.g. "zh-Hant" matches both "zh-Hant-TW" and
+// "zh-Hant-HK"). Lookup returns at most one tag: for each range in
+// preference order it progressively truncates the range's rightmost subtag
+// until some supported tag matches, stopping at the first range that
+// produces a match.
+func Filter(header string, supported []string, mode FilterMode) []string {
+	if header == "" || len(supported) == 0 {
+		return []string{}
+	}
+
+	ranges := splitRanges(header)
+
+	if mode == Filtering {
+		seen := make(map[string]bool)
+		result := make([]string, 0)
+		for _, rng := range ranges {
+			for _, s := range supported {
+				if !seen[s] && rangeMatchesTag(rng, s) {
+					result = append(result, s)
+					seen[s] = true
+				}
+			}
+		}
+		return result
+	}
+
+	for _, rng := range ranges {
+		subs := effectiveRangeSubtags(rng)
+		for len(subs) > 0 {
+			candidate := strings.Join(subs, "-")
+			for _, s := range supported {
+				if rangeMatchesTag(candidate, s) {
+					return []string{s}
+				}
+			}
+			subs = subs[:len(subs)-1]
+		}
+	}
+	return []string{}
+}
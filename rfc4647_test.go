@@ -0,0 +1,86 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterFiltering(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		supported []string
+		expected  []string
+	}{
+		{
+			name:      "Prefix extension match",
+			header:    "zh-Hant",
+			supported: []string{"zh-Hant-TW", "zh-Hant-HK", "zh-Hans"},
+			expected:  []string{"zh-Hant-TW", "zh-Hant-HK"},
+		},
+		{
+			name:      "Extension subtag is ignored when matching",
+			header:    "en-US-u-ca-gregory",
+			supported: []string{"en-US", "en-GB"},
+			expected:  []string{"en-US"},
+		},
+		{
+			name:      "No match",
+			header:    "de",
+			supported: []string{"en-US", "fr-FR"},
+			expected:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Filter(tt.header, tt.supported, Filtering)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Filter(%q, %v, Filtering) = %v, want %v", tt.header, tt.supported, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterLookup(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		supported []string
+		expected  []string
+	}{
+		{
+			name:      "Falls back through de-CH then de",
+			header:    "de-CH-1996",
+			supported: []string{"de", "fr"},
+			expected:  []string{"de"},
+		},
+		{
+			name:      "Prefers the most specific supported tag available",
+			header:    "de-CH-1996",
+			supported: []string{"de", "de-CH"},
+			expected:  []string{"de-CH"},
+		},
+		{
+			name:      "Truncates past an extension singleton",
+			header:    "zh-Hant-CN-x-private",
+			supported: []string{"zh-Hant"},
+			expected:  []string{"zh-Hant"},
+		},
+		{
+			name:      "No match anywhere",
+			header:    "de",
+			supported: []string{"en", "fr"},
+			expected:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Filter(tt.header, tt.supported, Lookup)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Filter(%q, %v, Lookup) = %v, want %v", tt.header, tt.supported, result, tt.expected)
+			}
+		})
+	}
+}